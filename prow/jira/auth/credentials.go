@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth implements a small, pluggable store of named credentials for
+// talking to Jira instances. It intentionally mirrors the credential model
+// used by git-bug's bridge/core/auth package: credentials are identified by
+// a name, are one of a handful of kinds, and are resolved lazily from
+// whatever backing store the deployment wires up (typically Prow's secret
+// agent reading mounted Kubernetes secrets).
+package auth
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Credential is a secret used to authenticate against a Jira instance. It
+// carries no behavior beyond Kind; prow/jira's newClientForCredential turns
+// each concrete type into the matching client authentication option.
+type Credential interface {
+	// Kind identifies the credential type, e.g. for logging or validation.
+	Kind() string
+}
+
+// TokenCredential authenticates using a bearer/API token.
+type TokenCredential struct {
+	Token string
+}
+
+func (TokenCredential) Kind() string { return "token" }
+
+// LoginPasswordCredential authenticates using basic auth.
+type LoginPasswordCredential struct {
+	Login    string
+	Password string
+}
+
+func (LoginPasswordCredential) Kind() string { return "login-password" }
+
+// PATCredential authenticates using a Jira personal access token, sent as a
+// Bearer token. Jira Server/Data Center instances that disable basic auth
+// require this.
+type PATCredential struct {
+	Token string
+}
+
+func (PATCredential) Kind() string { return "pat" }
+
+// Store resolves a named credential reference to a concrete Credential. The
+// name is whatever the plugin config's credential_ref points at; Store
+// implementations are free to back this with Kubernetes secrets, files on
+// disk, or anything else.
+type Store interface {
+	Get(name string) (Credential, error)
+}
+
+// StaticStore is a Store backed by an in-memory map, primarily useful for
+// tests and for small deployments that load all credentials up front (e.g.
+// from a single mounted secret directory) rather than fetching lazily.
+type StaticStore struct {
+	mu          sync.RWMutex
+	credentials map[string]Credential
+}
+
+// NewStaticStore returns a Store pre-populated with the given credentials.
+func NewStaticStore(credentials map[string]Credential) *StaticStore {
+	copied := make(map[string]Credential, len(credentials))
+	for name, cred := range credentials {
+		copied[name] = cred
+	}
+	return &StaticStore{credentials: copied}
+}
+
+// Get implements Store.
+func (s *StaticStore) Get(name string) (Credential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cred, ok := s.credentials[name]
+	if !ok {
+		return nil, fmt.Errorf("no credential registered for ref %q", name)
+	}
+	return cred, nil
+}
+
+// Set registers or replaces a credential under name. It exists so a
+// secret-backed wrapper can refresh StaticStore on secret change events.
+func (s *StaticStore) Set(name string, cred Credential) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credentials[name] = cred
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import "testing"
+
+func TestStaticStoreGet(t *testing.T) {
+	store := NewStaticStore(map[string]Credential{
+		"redhat": TokenCredential{Token: "tok"},
+	})
+
+	cred, err := store.Get("redhat")
+	if err != nil {
+		t.Fatalf("Get() returned unexpected error: %v", err)
+	}
+	token, ok := cred.(TokenCredential)
+	if !ok {
+		t.Fatalf("Get() returned credential of type %T, want TokenCredential", cred)
+	}
+	if token.Token != "tok" {
+		t.Errorf("Get() token = %q, want %q", token.Token, "tok")
+	}
+
+	if _, err := store.Get("missing"); err == nil {
+		t.Error("Get() for unregistered name expected an error, got nil")
+	}
+}
+
+func TestStaticStoreSet(t *testing.T) {
+	store := NewStaticStore(nil)
+	if _, err := store.Get("apache"); err == nil {
+		t.Fatal("Get() before Set() expected an error, got nil")
+	}
+
+	store.Set("apache", LoginPasswordCredential{Login: "bot", Password: "hunter2"})
+
+	cred, err := store.Get("apache")
+	if err != nil {
+		t.Fatalf("Get() returned unexpected error: %v", err)
+	}
+	if cred.Kind() != "login-password" {
+		t.Errorf("Get() kind = %q, want %q", cred.Kind(), "login-password")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jira wraps github.com/andygrunwald/go-jira with the subset of
+// functionality Prow plugins need, behind an interface so they can be
+// tested without a live Jira instance.
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Client is the interface Prow plugins use to talk to a single Jira
+// instance.
+type Client interface {
+	// JiraURL returns the base URL this client talks to, e.g. for
+	// constructing browse links.
+	JiraURL() string
+	GetIssue(id string) (*jira.Issue, error)
+	// GetIssueWithFields is GetIssue, but restricted to the given field
+	// names to avoid pulling the entire issue payload when only a few
+	// fields (e.g. for an info card) are needed.
+	GetIssueWithFields(id string, fields ...string) (*jira.Issue, error)
+	GetRemoteLinks(id string) ([]jira.RemoteLink, error)
+	AddRemoteLink(id string, link *jira.RemoteLink) error
+	// GetProjects returns all projects visible to this client's
+	// credentials, for building a project-key allow-list.
+	GetProjects() ([]jira.Project, error)
+	// SearchIssues runs a JQL query and returns the matching issues, for
+	// batching up what would otherwise be one GetIssue call per candidate.
+	SearchIssues(jql string) ([]jira.Issue, error)
+	// GetTransitions returns the transitions currently available on id, for
+	// resolving a configured transition name to the ID DoTransition needs.
+	GetTransitions(id string) ([]jira.Transition, error)
+	// DoTransition applies the transition identified by transitionID to id.
+	DoTransition(id, transitionID string) error
+	// AddComment adds a plain-text comment to id.
+	AddComment(id, comment string) error
+	// CreateIssue files a new issue of issueType in project.
+	CreateIssue(project, issueType, summary, description string) (*jira.Issue, error)
+	// DeleteRemoteLink removes a remote link previously added with
+	// AddRemoteLink.
+	DeleteRemoteLink(id string, linkID int) error
+}
+
+// apiError wraps a failed Jira API call. It keeps the HTTP status code
+// alongside the library's own error message so callers like IsNotFound
+// don't need to type-assert on *jira.Response, which does not implement
+// the error interface.
+type apiError struct {
+	statusCode int
+	err        error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+func (e *apiError) Unwrap() error { return e.err }
+
+// wrapError turns a (*jira.Response, error) pair from the go-jira client
+// into a proper error value, preserving the status code for IsNotFound.
+func wrapError(response *jira.Response, err error) error {
+	if response == nil {
+		return err
+	}
+	return &apiError{statusCode: response.StatusCode, err: jira.NewJiraError(response, err)}
+}
+
+// IsNotFound returns true if err represents a Jira 404.
+func IsNotFound(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.statusCode == http.StatusNotFound
+}
+
+// Option configures a Client returned by NewClient.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	basicAuthLogin    string
+	basicAuthPassword string
+	bearerToken       string
+}
+
+// WithBasicAuth authenticates using a Jira username/password (or, for Jira
+// Cloud, an email/API-token pair).
+func WithBasicAuth(login, password string) Option {
+	return func(o *clientOptions) {
+		o.basicAuthLogin = login
+		o.basicAuthPassword = password
+	}
+}
+
+// WithBearerToken authenticates using a bearer token, as required by Jira
+// personal access tokens on Server/Data Center instances.
+func WithBearerToken(token string) Option {
+	return func(o *clientOptions) {
+		o.bearerToken = token
+	}
+}
+
+type client struct {
+	url      string
+	upstream *jira.Client
+}
+
+// NewClient constructs a Client for the Jira instance at url, authenticated
+// according to the given Option.
+func NewClient(url string, opts ...Option) (Client, error) {
+	options := &clientOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var httpClient *http.Client
+	switch {
+	case options.bearerToken != "":
+		httpClient = (&jira.PATAuthTransport{Token: options.bearerToken}).Client()
+	case options.basicAuthLogin != "":
+		httpClient = (&jira.BasicAuthTransport{Username: options.basicAuthLogin, Password: options.basicAuthPassword}).Client()
+	default:
+		return nil, fmt.Errorf("no authentication method configured for jira client")
+	}
+
+	upstream, err := jira.NewClient(httpClient, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct jira client: %w", err)
+	}
+	return &client{url: url, upstream: upstream}, nil
+}
+
+func (c *client) JiraURL() string {
+	return c.url
+}
+
+func (c *client) GetIssue(id string) (*jira.Issue, error) {
+	issue, response, err := c.upstream.Issue.Get(id, nil)
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	return issue, nil
+}
+
+func (c *client) GetIssueWithFields(id string, fields ...string) (*jira.Issue, error) {
+	issue, response, err := c.upstream.Issue.Get(id, &jira.GetQueryOptions{Fields: strings.Join(fields, ",")})
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	return issue, nil
+}
+
+func (c *client) GetRemoteLinks(id string) ([]jira.RemoteLink, error) {
+	links, response, err := c.upstream.Issue.GetRemoteLinks(id)
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	if links == nil {
+		return nil, nil
+	}
+	return *links, nil
+}
+
+func (c *client) AddRemoteLink(id string, link *jira.RemoteLink) error {
+	_, response, err := c.upstream.Issue.AddRemoteLink(id, link)
+	if err != nil {
+		return wrapError(response, err)
+	}
+	return nil
+}
+
+func (c *client) GetProjects() ([]jira.Project, error) {
+	projects, response, err := c.upstream.Project.GetList()
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	if projects == nil {
+		return nil, nil
+	}
+	return *projects, nil
+}
+
+func (c *client) SearchIssues(jql string) ([]jira.Issue, error) {
+	issues, response, err := c.upstream.Issue.Search(jql, nil)
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	return issues, nil
+}
+
+func (c *client) GetTransitions(id string) ([]jira.Transition, error) {
+	transitions, response, err := c.upstream.Issue.GetTransitions(id)
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	return transitions, nil
+}
+
+func (c *client) DoTransition(id, transitionID string) error {
+	response, err := c.upstream.Issue.DoTransition(id, transitionID)
+	if err != nil {
+		return wrapError(response, err)
+	}
+	return nil
+}
+
+func (c *client) AddComment(id, comment string) error {
+	_, response, err := c.upstream.Issue.AddComment(id, &jira.Comment{Body: comment})
+	if err != nil {
+		return wrapError(response, err)
+	}
+	return nil
+}
+
+func (c *client) CreateIssue(project, issueType, summary, description string) (*jira.Issue, error) {
+	issue := &jira.Issue{
+		Fields: &jira.IssueFields{
+			Project:     jira.Project{Key: project},
+			Type:        jira.IssueType{Name: issueType},
+			Summary:     summary,
+			Description: description,
+		},
+	}
+	created, response, err := c.upstream.Issue.Create(issue)
+	if err != nil {
+		return nil, wrapError(response, err)
+	}
+	return created, nil
+}
+
+func (c *client) DeleteRemoteLink(id string, linkID int) error {
+	response, err := c.upstream.Issue.DeleteRemoteLink(id, linkID)
+	if err != nil {
+		return wrapError(response, err)
+	}
+	return nil
+}
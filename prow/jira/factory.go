@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/test-infra/prow/jira/auth"
+)
+
+// ClientResolver resolves the Client to use for a given org/repo. It is the
+// seam that lets callers that used to hold a single Client stay agnostic of
+// how many Jira instances a deployment actually talks to.
+type ClientResolver interface {
+	ClientFor(org, repo string) (Client, error)
+}
+
+// Instance describes a single Jira tenant: where it lives and which
+// credential in the auth.Store authenticates against it.
+type Instance struct {
+	URL           string
+	CredentialRef string
+}
+
+// InstanceMap maps a per-deployment instance name (e.g. "redhat", "apache")
+// to its connection details.
+type InstanceMap map[string]Instance
+
+// ClientFactory is the default ClientResolver. It builds one Client per
+// configured instance, lazily and once, then caches it for reuse.
+type ClientFactory struct {
+	instances     InstanceMap
+	orgDefaults   map[string]string
+	repoOverrides map[string]string
+	store         auth.Store
+
+	mu      sync.Mutex
+	clients map[string]Client
+}
+
+// NewClientFactory constructs a ClientResolver from a set of named
+// instances, org/repo routing, and a credential store. The credential store
+// is typically backed by Prow's secret agent reading mounted Kubernetes
+// secrets.
+func NewClientFactory(instances InstanceMap, orgDefaults, repoOverrides map[string]string, store auth.Store) *ClientFactory {
+	return &ClientFactory{
+		instances:     instances,
+		orgDefaults:   orgDefaults,
+		repoOverrides: repoOverrides,
+		store:         store,
+		clients:       map[string]Client{},
+	}
+}
+
+// ClientFor implements ClientResolver.
+func (f *ClientFactory) ClientFor(org, repo string) (Client, error) {
+	instanceName, err := f.instanceFor(org, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if client, ok := f.clients[instanceName]; ok {
+		return client, nil
+	}
+
+	instance, ok := f.instances[instanceName]
+	if !ok {
+		return nil, fmt.Errorf("jira instance %q referenced but not defined", instanceName)
+	}
+	cred, err := f.store.Get(instance.CredentialRef)
+	if err != nil {
+		return nil, fmt.Errorf("resolving credential %q for instance %q: %w", instance.CredentialRef, instanceName, err)
+	}
+
+	client, err := newClientForCredential(instance.URL, cred)
+	if err != nil {
+		return nil, fmt.Errorf("building jira client for instance %q: %w", instanceName, err)
+	}
+	f.clients[instanceName] = client
+	return client, nil
+}
+
+func (f *ClientFactory) instanceFor(org, repo string) (string, error) {
+	if name, ok := f.repoOverrides[fmt.Sprintf("%s/%s", org, repo)]; ok {
+		return name, nil
+	}
+	if name, ok := f.orgDefaults[org]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("no jira instance configured for %s/%s", org, repo)
+}
+
+// newClientForCredential adapts an auth.Credential into whatever NewClient
+// expects for that authentication style.
+func newClientForCredential(url string, cred auth.Credential) (Client, error) {
+	switch c := cred.(type) {
+	case auth.TokenCredential:
+		return NewClient(url, WithBearerToken(c.Token))
+	case auth.PATCredential:
+		return NewClient(url, WithBearerToken(c.Token))
+	case auth.LoginPasswordCredential:
+		return NewClient(url, WithBasicAuth(c.Login, c.Password))
+	default:
+		return nil, fmt.Errorf("unsupported credential kind %q", cred.Kind())
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"testing"
+
+	"k8s.io/test-infra/prow/jira/auth"
+)
+
+func TestClientFactoryInstanceFor(t *testing.T) {
+	f := &ClientFactory{
+		orgDefaults:   map[string]string{"kubernetes": "apache"},
+		repoOverrides: map[string]string{"kubernetes/test-infra": "redhat"},
+	}
+
+	testCases := []struct {
+		name    string
+		org     string
+		repo    string
+		want    string
+		wantErr bool
+	}{
+		{name: "repo override wins", org: "kubernetes", repo: "test-infra", want: "redhat"},
+		{name: "falls back to org default", org: "kubernetes", repo: "kubernetes", want: "apache"},
+		{name: "no config at all", org: "unknown", repo: "unknown", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := f.instanceFor(tc.org, tc.repo)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("instanceFor() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("instanceFor() returned unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("instanceFor() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClientFactoryClientFor(t *testing.T) {
+	instances := InstanceMap{
+		"redhat": {URL: "https://issues.redhat.com", CredentialRef: "redhat-token"},
+	}
+	store := auth.NewStaticStore(map[string]auth.Credential{
+		"redhat-token": auth.TokenCredential{Token: "tok"},
+	})
+	f := NewClientFactory(instances, nil, map[string]string{"kubernetes/test-infra": "redhat"}, store)
+
+	client, err := f.ClientFor("kubernetes", "test-infra")
+	if err != nil {
+		t.Fatalf("ClientFor() returned unexpected error: %v", err)
+	}
+	if client.JiraURL() != "https://issues.redhat.com" {
+		t.Errorf("JiraURL() = %q, want %q", client.JiraURL(), "https://issues.redhat.com")
+	}
+
+	again, err := f.ClientFor("kubernetes", "test-infra")
+	if err != nil {
+		t.Fatalf("ClientFor() returned unexpected error on second call: %v", err)
+	}
+	if client != again {
+		t.Error("ClientFor() did not return the cached client on a second call for the same instance")
+	}
+
+	if _, err := f.ClientFor("unknown", "unknown"); err == nil {
+		t.Error("ClientFor() for an unrouted repo expected an error, got nil")
+	}
+}
+
+func TestClientFactoryClientForMissingCredential(t *testing.T) {
+	instances := InstanceMap{
+		"redhat": {URL: "https://issues.redhat.com", CredentialRef: "does-not-exist"},
+	}
+	f := NewClientFactory(instances, map[string]string{"kubernetes": "redhat"}, nil, auth.NewStaticStore(nil))
+
+	if _, err := f.ClientFor("kubernetes", "test-infra"); err == nil {
+		t.Error("ClientFor() with an unresolvable credential ref expected an error, got nil")
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProjectCache caches the set of valid project keys for a Client so that
+// filtering issue-key candidates against "does this project actually
+// exist" doesn't require a GetProjects call on every webhook event.
+type ProjectCache struct {
+	client Client
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	keys    map[string]bool
+	fetched time.Time
+}
+
+// NewProjectCache returns a ProjectCache that refreshes from client at most
+// once per ttl.
+func NewProjectCache(client Client, ttl time.Duration) *ProjectCache {
+	return &ProjectCache{client: client, ttl: ttl}
+}
+
+// Keys returns the set of known project keys, upper-cased, refreshing from
+// the client if the cache is empty or stale.
+func (c *ProjectCache) Keys() (map[string]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys != nil && time.Since(c.fetched) < c.ttl {
+		return c.keys, nil
+	}
+
+	projects, err := c.client.GetProjects()
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		keys[strings.ToUpper(project.Key)] = true
+	}
+	c.keys = keys
+	c.fetched = time.Now()
+	return c.keys, nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/test-infra/prow/github"
+	jiraclient "k8s.io/test-infra/prow/jira"
+	"k8s.io/test-infra/prow/plugins"
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
+)
+
+// jiraCommandRegex matches a `/jira <verb> <args>` line, the way other Prow
+// plugins parse their own slash commands out of a comment body.
+var jiraCommandRegex = regexp.MustCompile(`(?mi)^/jira\s+(refresh|link|unlink|create)\s*(.*)$`)
+
+// command is one parsed `/jira ...` invocation.
+type command struct {
+	verb string
+	args string
+}
+
+// parseCommand returns the first `/jira ...` command found in body, if any.
+// Only one command per comment is supported, matching how the regex-linker
+// in handle only needs to run once per comment too.
+func parseCommand(body string) (command, bool) {
+	match := jiraCommandRegex.FindStringSubmatch(body)
+	if match == nil {
+		return command{}, false
+	}
+	return command{verb: strings.ToLower(match[1]), args: strings.TrimSpace(match[2])}, true
+}
+
+func handleCommand(jc jiraclient.Client, pc plugins.Agent, e *github.GenericCommentEvent, cmd command) error {
+	switch cmd.verb {
+	case "refresh":
+		return commandRefresh(jc, pc, e)
+	case "link":
+		return commandLink(jc, pc, e, cmd.args)
+	case "unlink":
+		return commandUnlink(jc, pc, e, cmd.args)
+	case "create":
+		return commandCreate(jc, pc, e, cmd.args)
+	default:
+		return nil
+	}
+}
+
+// commandRefresh re-derives the set of Jira issues referenced by the
+// underlying PR/issue (not just the triggering comment) and re-runs the
+// linker against it, so a reference added before the plugin was enabled
+// gets picked up.
+func commandRefresh(jc jiraclient.Client, pc plugins.Agent, e *github.GenericCommentEvent) error {
+	issue, err := pc.GitHubClient.GetIssue(e.Repo.Owner.Login, e.Repo.Name, e.Number)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s/%s#%d: %w", e.Repo.Owner.Login, e.Repo.Name, e.Number, err)
+	}
+	repoConfig := pc.PluginConfig.Jira.RepoConfig(e.Repo.Owner.Login, e.Repo.Name)
+	refreshEvent := *e
+	refreshEvent.Body = issue.Body
+	refreshEvent.IssueTitle = issue.Title
+	refreshEvent.CommentID = nil
+	return handle(jc, pc.GitHubClient, pc.Logger, &refreshEvent, repoConfig)
+}
+
+// commandLink force-links the given issue keys, bypassing the regex and
+// project-allow-list prefilter, for references the automatic matcher
+// misses (lowercase keys, unusual separators, etc). Each key is still
+// verified against Jira before being linked. A lookup failure on one key
+// doesn't abort the rest: every key is attempted, and whatever got
+// verified is still linked and reflected in the comment before the error
+// is returned.
+func commandLink(jc jiraclient.Client, pc plugins.Agent, e *github.GenericCommentEvent, args string) error {
+	keys := strings.Fields(args)
+	if len(keys) == 0 {
+		return pc.GitHubClient.CreateComment(e.Repo.Owner.Login, e.Repo.Name, e.Number, "`/jira link` needs at least one issue key, e.g. `/jira link ABC-123`")
+	}
+
+	var verified []string
+	var failures []string
+	for _, key := range keys {
+		key = strings.ToUpper(key)
+		if _, err := jc.GetIssue(key); err != nil {
+			if jiraclient.IsNotFound(err) {
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		verified = append(verified, key)
+		if err := upsertGitHubLinkToIssue(pc.Logger, key, jc, e); err != nil {
+			pc.Logger.WithField("Issue", key).WithError(err).Error("Failed to ensure GitHub link on Jira issue")
+		}
+	}
+
+	if len(verified) > 0 {
+		if err := updateComment(e, verified, jc.JiraURL(), pc.GitHubClient); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to look up %d issue(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// commandUnlink removes the remote link and inline markdown for issueKey.
+// The markdown was inserted into the underlying PR/issue body (or an
+// earlier comment), not into the comment carrying `/jira unlink` itself, so
+// this fetches and edits that body the same way commandRefresh does rather
+// than operating on e.Body.
+func commandUnlink(jc jiraclient.Client, pc plugins.Agent, e *github.GenericCommentEvent, args string) error {
+	key := strings.ToUpper(strings.TrimSpace(args))
+	if key == "" {
+		return pc.GitHubClient.CreateComment(e.Repo.Owner.Login, e.Repo.Name, e.Number, "`/jira unlink` needs an issue key, e.g. `/jira unlink ABC-123`")
+	}
+
+	url := e.HTMLURL
+	if idx := strings.Index(url, "#"); idx != -1 {
+		url = url[:idx]
+	}
+	links, err := jc.GetRemoteLinks(key)
+	if err != nil {
+		return fmt.Errorf("failed to get remote links: %w", err)
+	}
+	for _, link := range links {
+		if link.Object.URL == url {
+			if err := jc.DeleteRemoteLink(key, link.ID); err != nil {
+				return fmt.Errorf("failed to delete remote link: %w", err)
+			}
+			break
+		}
+	}
+
+	issue, err := pc.GitHubClient.GetIssue(e.Repo.Owner.Login, e.Repo.Name, e.Number)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s/%s#%d: %w", e.Repo.Owner.Login, e.Repo.Name, e.Number, err)
+	}
+	withoutLink := removeLinkFromComment(issue.Body, key, jc.JiraURL())
+	if withoutLink == issue.Body {
+		return nil
+	}
+	issue.Body = withoutLink
+	_, err = pc.GitHubClient.EditIssue(e.Repo.Owner.Login, e.Repo.Name, e.Number, issue)
+	return err
+}
+
+func removeLinkFromComment(body, issueKey, jiraBaseURL string) string {
+	markdown := fmt.Sprintf("[%s](%s/browse/%s)", issueKey, jiraBaseURL, issueKey)
+	return strings.ReplaceAll(body, markdown, issueKey)
+}
+
+// commandCreate files a new Jira issue in project with the given title and
+// links the triggering PR/issue to it. It is gated on jiraconfig.Config's
+// Creators list so arbitrary commenters cannot file issues on someone
+// else's Jira instance.
+func commandCreate(jc jiraclient.Client, pc plugins.Agent, e *github.GenericCommentEvent, args string) error {
+	if !isAuthorizedCreator(pc.GitHubClient, pc.PluginConfig.Jira, e.User.Login) {
+		return pc.GitHubClient.CreateComment(e.Repo.Owner.Login, e.Repo.Name, e.Number,
+			fmt.Sprintf("@%s: you are not authorized to create Jira issues via `/jira create`.", e.User.Login))
+	}
+
+	project, title, ok := parseCreateArgs(args)
+	if !ok {
+		return pc.GitHubClient.CreateComment(e.Repo.Owner.Login, e.Repo.Name, e.Number,
+			"`/jira create` needs a project and a quoted title, e.g. `/jira create ABC \"Fix the thing\"`")
+	}
+
+	description := fmt.Sprintf("Filed from %s", e.HTMLURL)
+	issue, err := jc.CreateIssue(project, "Task", title, description)
+	if err != nil {
+		return fmt.Errorf("failed to create jira issue: %w", err)
+	}
+
+	if err := upsertGitHubLinkToIssue(pc.Logger, issue.Key, jc, e); err != nil {
+		pc.Logger.WithField("Issue", issue.Key).WithError(err).Error("Failed to ensure GitHub link on newly created jira issue")
+	}
+
+	// The new key never appears as a literal substring of the triggering
+	// comment, so updateComment's insertLinksIntoComment linker would be a
+	// no-op here; post a confirmation comment with the key instead.
+	return pc.GitHubClient.CreateComment(e.Repo.Owner.Login, e.Repo.Name, e.Number,
+		fmt.Sprintf("Filed [%s](%s/browse/%s).", issue.Key, jc.JiraURL(), issue.Key))
+}
+
+// parseCreateArgs parses `PROJECT "quoted title"` out of the raw command
+// arguments.
+func parseCreateArgs(args string) (project, title string, ok bool) {
+	match := createArgsRegex.FindStringSubmatch(args)
+	if match == nil {
+		return "", "", false
+	}
+	return strings.ToUpper(match[1]), match[2], true
+}
+
+var createArgsRegex = regexp.MustCompile(`^(\S+)\s+"([^"]+)"$`)
+
+// isAuthorizedCreator reports whether user may run `/jira create`: either
+// because they are listed directly in cfg.Creators, or because they are a
+// member of an org listed there.
+func isAuthorizedCreator(ghc githubClient, cfg jiraconfig.Config, user string) bool {
+	for _, entry := range cfg.Creators {
+		if strings.EqualFold(entry, user) {
+			return true
+		}
+	}
+	for _, entry := range cfg.Creators {
+		if member, err := ghc.IsMember(entry, user); err == nil && member {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/plugins"
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
+)
+
+func TestParseCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		wantOK   bool
+		wantVerb string
+		wantArgs string
+	}{
+		{name: "refresh", body: "/jira refresh", wantOK: true, wantVerb: "refresh"},
+		{name: "link with args", body: "/jira link ABC-123", wantOK: true, wantVerb: "link", wantArgs: "ABC-123"},
+		{name: "uppercase verb normalized", body: "/JIRA UNLINK ABC-123", wantOK: true, wantVerb: "unlink", wantArgs: "ABC-123"},
+		{name: "unrelated comment", body: "just chatting, no command here", wantOK: false},
+		{name: "unknown verb", body: "/jira bogus", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, ok := parseCommand(tc.body)
+			if ok != tc.wantOK {
+				t.Fatalf("parseCommand() ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if cmd.verb != tc.wantVerb {
+				t.Errorf("parseCommand() verb = %q, want %q", cmd.verb, tc.wantVerb)
+			}
+			if cmd.args != tc.wantArgs {
+				t.Errorf("parseCommand() args = %q, want %q", cmd.args, tc.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseCreateArgs(t *testing.T) {
+	testCases := []struct {
+		name        string
+		args        string
+		wantProject string
+		wantTitle   string
+		wantOK      bool
+	}{
+		{name: "valid", args: `ABC "Fix the thing"`, wantProject: "ABC", wantTitle: "Fix the thing", wantOK: true},
+		{name: "lowercase project normalized", args: `abc "Fix the thing"`, wantProject: "ABC", wantTitle: "Fix the thing", wantOK: true},
+		{name: "missing quotes", args: `ABC Fix the thing`, wantOK: false},
+		{name: "missing project", args: `"Fix the thing"`, wantOK: false},
+		{name: "empty", args: "", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			project, title, ok := parseCreateArgs(tc.args)
+			if ok != tc.wantOK {
+				t.Fatalf("parseCreateArgs() ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if project != tc.wantProject {
+				t.Errorf("parseCreateArgs() project = %q, want %q", project, tc.wantProject)
+			}
+			if title != tc.wantTitle {
+				t.Errorf("parseCreateArgs() title = %q, want %q", title, tc.wantTitle)
+			}
+		})
+	}
+}
+
+// fakeGithubClient is a minimal in-memory githubClient for exercising the
+// command handlers without a live GitHub instance.
+type fakeGithubClient struct {
+	issue       *github.Issue
+	editedIssue *github.Issue
+	comments    []string
+	editedID    int
+	editedBody  string
+	orgMembers  map[string][]string
+}
+
+func (f *fakeGithubClient) EditComment(org, repo string, id int, comment string) error {
+	f.editedID = id
+	f.editedBody = comment
+	return nil
+}
+
+func (f *fakeGithubClient) GetIssue(org, repo string, number int) (*github.Issue, error) {
+	if f.issue == nil {
+		return nil, fmt.Errorf("no issue configured")
+	}
+	issueCopy := *f.issue
+	return &issueCopy, nil
+}
+
+func (f *fakeGithubClient) EditIssue(org, repo string, number int, issue *github.Issue) (*github.Issue, error) {
+	f.editedIssue = issue
+	return issue, nil
+}
+
+func (f *fakeGithubClient) CreateComment(org, repo string, number int, comment string) error {
+	f.comments = append(f.comments, comment)
+	return nil
+}
+
+func (f *fakeGithubClient) ListIssueComments(org, repo string, number int) ([]github.IssueComment, error) {
+	return nil, nil
+}
+
+func (f *fakeGithubClient) IsMember(org, user string) (bool, error) {
+	for _, member := range f.orgMembers[org] {
+		if member == user {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeCommandJiraClient extends fakeJiraClient with the bits commands.go
+// needs that transitionIssue doesn't: GetIssue, project listing and search
+// (used by handle via commandRefresh), remote links, and creation.
+type fakeCommandJiraClient struct {
+	fakeJiraClient
+	knownIssues  map[string]bool
+	removedLinks []int
+	remoteLinks  []jira.RemoteLink
+	created      *jira.Issue
+}
+
+func (f *fakeCommandJiraClient) GetIssue(id string) (*jira.Issue, error) {
+	if !f.knownIssues[id] {
+		return nil, errNotFound
+	}
+	return &jira.Issue{Key: id}, nil
+}
+
+var errNotFound = fmt.Errorf("issue not found")
+
+func (f *fakeCommandJiraClient) GetProjects() ([]jira.Project, error) {
+	var projects []jira.Project
+	for key := range f.knownIssues {
+		projects = append(projects, jira.Project{Key: projectOf(key)})
+	}
+	return projects, nil
+}
+
+func (f *fakeCommandJiraClient) SearchIssues(jql string) ([]jira.Issue, error) {
+	var issues []jira.Issue
+	for key := range f.knownIssues {
+		issues = append(issues, jira.Issue{Key: key})
+	}
+	return issues, nil
+}
+
+func (f *fakeCommandJiraClient) GetRemoteLinks(id string) ([]jira.RemoteLink, error) {
+	return f.remoteLinks, nil
+}
+
+func (f *fakeCommandJiraClient) DeleteRemoteLink(id string, linkID int) error {
+	f.removedLinks = append(f.removedLinks, linkID)
+	return nil
+}
+
+func (f *fakeCommandJiraClient) CreateIssue(project, issueType, summary, description string) (*jira.Issue, error) {
+	f.created = &jira.Issue{Key: project + "-1"}
+	return f.created, nil
+}
+
+func testAgent(ghc *fakeGithubClient, cfg jiraconfig.Config) plugins.Agent {
+	return plugins.Agent{
+		GitHubClient: ghc,
+		PluginConfig: &plugins.Configuration{Jira: cfg},
+		Logger:       logrus.NewEntry(logrus.StandardLogger()),
+	}
+}
+
+func TestCommandRefresh(t *testing.T) {
+	ghc := &fakeGithubClient{issue: &github.Issue{Body: "See ABC-123 for details", Title: "fix: ABC-123"}}
+	jc := &fakeCommandJiraClient{knownIssues: map[string]bool{"ABC-123": true}}
+	e := &github.GenericCommentEvent{
+		Repo:      github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+		Number:    1,
+		CommentID: intPtr(42),
+	}
+
+	if err := commandRefresh(jc, testAgent(ghc, jiraconfig.Config{}), e); err != nil {
+		t.Fatalf("commandRefresh() returned unexpected error: %v", err)
+	}
+	if ghc.editedIssue == nil || !strings.Contains(ghc.editedIssue.Body, "[ABC-123]") {
+		t.Errorf("commandRefresh() expected the issue body to be edited with a link, got %v", ghc.editedIssue)
+	}
+}
+
+func TestCommandLink(t *testing.T) {
+	ghc := &fakeGithubClient{}
+	jc := &fakeCommandJiraClient{knownIssues: map[string]bool{"ABC-123": true}}
+	e := &github.GenericCommentEvent{
+		Repo:   github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+		Number: 1,
+		Body:   "please link abc-123",
+	}
+
+	if err := commandLink(jc, testAgent(ghc, jiraconfig.Config{}), e, "abc-123"); err != nil {
+		t.Fatalf("commandLink() returned unexpected error: %v", err)
+	}
+	if len(ghc.comments) != 0 {
+		t.Errorf("commandLink() should not post an error comment for a valid key, got %v", ghc.comments)
+	}
+
+	ghc = &fakeGithubClient{}
+	if err := commandLink(jc, testAgent(ghc, jiraconfig.Config{}), e, "NOPE-1"); err == nil {
+		t.Error("commandLink() expected an error when the jira lookup fails for a non-404 reason")
+	}
+}
+
+// TestCommandLinkPartialFailure covers a multi-key `/jira link` where one
+// key fails to look up: the lookup failure must not discard the keys that
+// were already verified, and updateComment must still run for them.
+func TestCommandLinkPartialFailure(t *testing.T) {
+	ghc := &fakeGithubClient{}
+	jc := &fakeCommandJiraClient{knownIssues: map[string]bool{"ABC-123": true}}
+	e := &github.GenericCommentEvent{
+		Repo:      github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+		Number:    1,
+		Body:      "please link ABC-123 and NOPE-1",
+		CommentID: intPtr(42),
+	}
+
+	err := commandLink(jc, testAgent(ghc, jiraconfig.Config{}), e, "ABC-123 NOPE-1")
+	if err == nil {
+		t.Fatal("commandLink() expected an error when one of several keys fails to look up, got nil")
+	}
+	if ghc.editedID != 42 || !strings.Contains(ghc.editedBody, "[ABC-123]") {
+		t.Errorf("commandLink() expected the successfully verified key to still be linked in the comment, got id=%d body=%q", ghc.editedID, ghc.editedBody)
+	}
+}
+
+func TestCommandUnlink(t *testing.T) {
+	ghc := &fakeGithubClient{issue: &github.Issue{Body: "See [ABC-123](https://issues.example.com/browse/ABC-123) for details"}}
+	jc := &fakeCommandJiraClient{
+		knownIssues: map[string]bool{"ABC-123": true},
+		remoteLinks: []jira.RemoteLink{{ID: 7, Object: &jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/1"}}},
+	}
+	jc.url = "https://issues.example.com"
+	e := &github.GenericCommentEvent{
+		Repo:    github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+		Number:  1,
+		HTMLURL: "https://github.com/org/repo/pull/1#issuecomment-1",
+		Body:    "/jira unlink ABC-123",
+	}
+
+	if err := commandUnlink(jc, testAgent(ghc, jiraconfig.Config{}), e, "ABC-123"); err != nil {
+		t.Fatalf("commandUnlink() returned unexpected error: %v", err)
+	}
+	if len(jc.removedLinks) != 1 || jc.removedLinks[0] != 7 {
+		t.Errorf("commandUnlink() removedLinks = %v, want [7]", jc.removedLinks)
+	}
+	if ghc.editedIssue == nil || strings.Contains(ghc.editedIssue.Body, "](") {
+		t.Errorf("commandUnlink() expected the underlying issue body's markdown link to be removed, got %q", ghc.editedIssue)
+	}
+	if !strings.Contains(ghc.editedIssue.Body, "ABC-123") {
+		t.Errorf("commandUnlink() expected the bare issue key to remain in the body, got %q", ghc.editedIssue.Body)
+	}
+}
+
+func TestCommandCreate(t *testing.T) {
+	ghc := &fakeGithubClient{}
+	jc := &fakeCommandJiraClient{}
+	jc.url = "https://issues.example.com"
+	e := &github.GenericCommentEvent{
+		Repo:    github.Repo{Owner: github.User{Login: "org"}, Name: "repo"},
+		Number:  1,
+		HTMLURL: "https://github.com/org/repo/pull/1",
+		User:    github.User{Login: "alice"},
+	}
+	cfg := jiraconfig.Config{Creators: []string{"alice"}}
+
+	if err := commandCreate(jc, testAgent(ghc, cfg), e, `ABC "Fix the thing"`); err != nil {
+		t.Fatalf("commandCreate() returned unexpected error: %v", err)
+	}
+	if jc.created == nil || jc.created.Key != "ABC-1" {
+		t.Fatalf("commandCreate() expected an issue to be filed in project ABC, got %v", jc.created)
+	}
+	if len(ghc.comments) != 1 || !strings.Contains(ghc.comments[0], "ABC-1") {
+		t.Errorf("commandCreate() expected a confirmation comment naming the new key, got %v", ghc.comments)
+	}
+
+	ghc = &fakeGithubClient{}
+	e.User.Login = "mallory"
+	if err := commandCreate(jc, testAgent(ghc, cfg), e, `ABC "Fix the thing"`); err != nil {
+		t.Fatalf("commandCreate() returned unexpected error: %v", err)
+	}
+	if len(ghc.comments) != 1 || !strings.Contains(ghc.comments[0], "not authorized") {
+		t.Errorf("commandCreate() expected an unauthorized-user comment, got %v", ghc.comments)
+	}
+}
+
+func TestIsAuthorizedCreator(t *testing.T) {
+	ghc := &fakeGithubClient{orgMembers: map[string][]string{"my-org": {"bob"}}}
+	cfg := jiraconfig.Config{Creators: []string{"alice", "my-org"}}
+
+	if !isAuthorizedCreator(ghc, cfg, "alice") {
+		t.Error("isAuthorizedCreator() expected true for a directly-listed user")
+	}
+	if !isAuthorizedCreator(ghc, cfg, "bob") {
+		t.Error("isAuthorizedCreator() expected true for a member of a listed org")
+	}
+	if isAuthorizedCreator(ghc, cfg, "mallory") {
+		t.Error("isAuthorizedCreator() expected false for an unrelated user")
+	}
+}
+
+func intPtr(i int) *int { return &i }
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/test-infra/prow/github"
+	jiraclient "k8s.io/test-infra/prow/jira"
+	"k8s.io/test-infra/prow/plugins/jira/jiracard"
+)
+
+// infoCardFields are the only fields fetched per issue for the info card,
+// to avoid pulling the entire issue payload just to render six columns.
+var infoCardFields = []string{"summary", "status", "assignee", "priority", "fixVersions"}
+
+// upsertInfoCard fetches the infoCardFields of each referenced issue and
+// posts (or idempotently edits) a single bot comment summarizing them
+// on the PR/issue e was raised against. The existing inline `[KEY](...)`
+// linking in the comment body is untouched; this is additive.
+func upsertInfoCard(jc jiraclient.Client, ghc githubClient, log *logrus.Entry, e *github.GenericCommentEvent, issueKeys []string) {
+	if len(issueKeys) == 0 {
+		return
+	}
+
+	issues := make([]*jira.Issue, 0, len(issueKeys))
+	for _, key := range issueKeys {
+		issue, err := jc.GetIssueWithFields(key, infoCardFields...)
+		if err != nil {
+			log.WithField("Issue", key).WithError(err).Error("Failed to get issue fields for info card")
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	if len(issues) == 0 {
+		return
+	}
+
+	body := jiracard.Render(issues, jc.JiraURL())
+	if err := upsertComment(ghc, e.Repo.Owner.Login, e.Repo.Name, e.Number, jiracard.Marker, body); err != nil {
+		log.WithError(err).Error("Failed to upsert jira info card comment")
+	}
+}
+
+// upsertComment creates a new comment containing body, or edits the
+// existing comment containing marker in place if one is already present.
+func upsertComment(ghc githubClient, org, repo string, number int, marker, body string) error {
+	comments, err := ghc.ListIssueComments(org, repo, number)
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+	for _, comment := range comments {
+		if !strings.Contains(comment.Body, marker) {
+			continue
+		}
+		if comment.Body == body {
+			return nil
+		}
+		return ghc.EditComment(org, repo, comment.ID, body)
+	}
+	return ghc.CreateComment(org, repo, number, body)
+}
@@ -31,6 +31,7 @@ import (
 	jiraclient "k8s.io/test-infra/prow/jira"
 	"k8s.io/test-infra/prow/pluginhelp"
 	"k8s.io/test-infra/prow/plugins"
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
 )
 
 const (
@@ -46,10 +47,24 @@ func init() {
 }
 
 func helpProvider(config *plugins.Configuration, _ []config.OrgRepo) (*pluginhelp.PluginHelp, error) {
-	// The Config field is omitted because this plugin is not configurable.
+	// The Config field is omitted because this plugin's config (instances,
+	// per-repo allow-lists, sync transitions, creators) is all operator
+	// facing and not something contributors need surfaced in `/help`.
 	pluginHelp := &pluginhelp.PluginHelp{
 		Description: "The Jira plugin links Pull Requests and Issues to Jira issues",
 	}
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/jira refresh|link|unlink|create",
+		Description: "Drives the jira plugin from a comment: re-link, force-link, unlink, or file a new Jira issue.",
+		Featured:    false,
+		WhoCanUse:   "Anyone can use refresh/link/unlink; `create` is restricted to the configured creators allow-list.",
+		Examples: []string{
+			"/jira refresh",
+			"/jira link ABC-123",
+			"/jira unlink ABC-123",
+			"/jira create ABC \"Fix the thing\"",
+		},
+	})
 	return pluginHelp, nil
 }
 
@@ -57,13 +72,41 @@ type githubClient interface {
 	EditComment(org, repo string, id int, comment string) error
 	GetIssue(org, repo string, number int) (*github.Issue, error)
 	EditIssue(org, repo string, number int, issue *github.Issue) (*github.Issue, error)
+	CreateComment(org, repo string, number int, comment string) error
+	ListIssueComments(org, repo string, number int) ([]github.IssueComment, error)
+	IsMember(org, user string) (bool, error)
 }
 
 func handleGenericComment(pc plugins.Agent, e github.GenericCommentEvent) error {
-	return handle(pc.JiraClient, pc.GitHubClient, pc.Logger, &e)
+	if e.Action == github.GenericCommentActionDeleted {
+		return nil
+	}
+
+	repoConfig := pc.PluginConfig.Jira.RepoConfig(e.Repo.Owner.Login, e.Repo.Name)
+
+	jc, err := resolveClient(pc, e.Repo.Owner.Login, e.Repo.Name)
+	if err != nil {
+		pc.Logger.WithError(err).WithField("repo", e.Repo.FullName).Debug("No jira instance configured for repo, ignoring comment")
+		return nil
+	}
+
+	if cmd, ok := parseCommand(e.Body); ok {
+		return handleCommand(jc, pc, &e, cmd)
+	}
+
+	return handle(jc, pc.GitHubClient, pc.Logger, &e, repoConfig)
+}
+
+// resolveClient picks the jiraclient.Client to use for org/repo, whether
+// the deployment is configured for one Jira instance or several.
+func resolveClient(pc plugins.Agent, org, repo string) (jiraclient.Client, error) {
+	if pc.JiraClientResolver != nil {
+		return pc.JiraClientResolver.ClientFor(org, repo)
+	}
+	return pc.JiraClient, nil
 }
 
-func handle(jc jiraclient.Client, ghc githubClient, log *logrus.Entry, e *github.GenericCommentEvent) error {
+func handle(jc jiraclient.Client, ghc githubClient, log *logrus.Entry, e *github.GenericCommentEvent, repoConfig jiraconfig.RepoConfig) error {
 	// Nothing to do on deletion
 	if e.Action == github.GenericCommentActionDeleted {
 		return nil
@@ -74,20 +117,27 @@ func handle(jc jiraclient.Client, ghc githubClient, log *logrus.Entry, e *github
 	if len(issueCandidateNames) == 0 {
 		return nil
 	}
+	candidates := sets.NewString(issueCandidateNames...).List()
 
+	var knownProjects map[string]bool
+	if keys, err := projectCacheFor(jc).Keys(); err != nil {
+		log.WithError(err).Warn("Failed to refresh jira project keys, falling back to configured allow-list only")
+	} else {
+		knownProjects = keys
+	}
+	candidates = filterCandidates(candidates, repoConfig, knownProjects)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	found, err := resolveIssues(jc, candidates, repoConfig)
+	if err != nil {
+		log.WithError(err).Error("Failed to resolve candidate jira issues")
+		return nil
+	}
 	referencedIssues := sets.String{}
-	for _, match := range issueCandidateNames {
-		if referencedIssues.Has(match) {
-			continue
-		}
-		_, err := jc.GetIssue(match)
-		if err != nil {
-			if !jiraclient.IsNotFound(err) {
-				log.WithError(err).WithField("Issue", match).Error("Failed to get issue")
-			}
-			continue
-		}
-		referencedIssues.Insert(match)
+	for key := range found {
+		referencedIssues.Insert(key)
 	}
 
 	wg := &sync.WaitGroup{}
@@ -106,6 +156,8 @@ func handle(jc jiraclient.Client, ghc githubClient, log *logrus.Entry, e *github
 	}
 	wg.Wait()
 
+	upsertInfoCard(jc, ghc, log, e, referencedIssues.List())
+
 	return nil
 }
 
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jiracard renders a Markdown "info card" summarizing a set of Jira
+// issues, for posting as a single bot-authored GitHub comment.
+package jiracard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Marker is embedded in every rendered card so a later render of the same
+// PR/issue can find and edit the existing comment instead of creating a
+// duplicate. Bump the version suffix if the table format changes in a way
+// that would confuse code parsing the marker out of old comments.
+const Marker = "<!-- prow-jira-info:v1 -->"
+
+// Render formats issues as a single Markdown table, prefixed with Marker.
+// Issues are rendered in the order given; callers that care about stable
+// ordering across edits should sort before calling.
+func Render(issues []*jira.Issue, jiraBaseURL string) string {
+	var b strings.Builder
+	b.WriteString(Marker)
+	b.WriteString("\n### Referenced Jira Issues\n\n")
+	b.WriteString("| Key | Summary | Status | Assignee | Priority | Fix Versions |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, issue := range issues {
+		b.WriteString(row(issue, jiraBaseURL))
+	}
+	return b.String()
+}
+
+func row(issue *jira.Issue, jiraBaseURL string) string {
+	assignee := "_unassigned_"
+	if issue.Fields.Assignee != nil {
+		assignee = issue.Fields.Assignee.DisplayName
+	}
+	var status string
+	if issue.Fields.Status != nil {
+		status = issue.Fields.Status.Name
+	}
+	var priority string
+	if issue.Fields.Priority != nil {
+		priority = issue.Fields.Priority.Name
+	}
+	fixVersions := make([]string, 0, len(issue.Fields.FixVersions))
+	for _, version := range issue.Fields.FixVersions {
+		fixVersions = append(fixVersions, version.Name)
+	}
+
+	link := fmt.Sprintf("[%s](%s/browse/%s)", issue.Key, jiraBaseURL, issue.Key)
+	return fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+		link, cellEscape(issue.Fields.Summary), cellEscape(status), cellEscape(assignee),
+		cellEscape(priority), cellEscape(strings.Join(fixVersions, ", ")))
+}
+
+// cellEscape makes s safe to place inside a single Markdown table cell: a
+// literal "|" would otherwise be parsed as a new column boundary, and a
+// newline would break the row onto multiple lines.
+func cellEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jiracard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+func TestRender(t *testing.T) {
+	issues := []*jira.Issue{
+		{
+			Key: "ABC-123",
+			Fields: &jira.IssueFields{
+				Summary:     "Fix the thing",
+				Status:      &jira.Status{Name: "In Progress"},
+				Assignee:    &jira.User{DisplayName: "Jane Doe"},
+				Priority:    &jira.Priority{Name: "High"},
+				FixVersions: []*jira.FixVersion{{Name: "v1.2"}, {Name: "v1.3"}},
+			},
+		},
+		{
+			Key:    "ABC-456",
+			Fields: &jira.IssueFields{Summary: "Unassigned issue"},
+		},
+	}
+
+	body := Render(issues, "https://issues.example.com")
+
+	if !strings.HasPrefix(body, Marker) {
+		t.Error("Render() output does not start with Marker")
+	}
+	if !strings.Contains(body, "[ABC-123](https://issues.example.com/browse/ABC-123)") {
+		t.Error("Render() missing expected link for ABC-123")
+	}
+	if !strings.Contains(body, "Jane Doe") || !strings.Contains(body, "v1.2, v1.3") {
+		t.Error("Render() missing expected assignee/fixVersions for ABC-123")
+	}
+	if !strings.Contains(body, "_unassigned_") {
+		t.Error("Render() expected _unassigned_ placeholder for issue with no assignee")
+	}
+}
+
+func TestRenderEscapesTableBreakingCharacters(t *testing.T) {
+	issues := []*jira.Issue{
+		{
+			Key: "ABC-789",
+			Fields: &jira.IssueFields{
+				Summary:  "Support foo | bar flag\nwith a second line",
+				Assignee: &jira.User{DisplayName: "Jane | Doe"},
+			},
+		},
+		{
+			Key:    "ABC-790",
+			Fields: &jira.IssueFields{Summary: "Unrelated issue"},
+		},
+	}
+
+	body := Render(issues, "https://issues.example.com")
+
+	rows := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	if len(rows) != 7 {
+		t.Fatalf("Render() produced %d lines, want 7 (marker + heading + blank line + header + separator + one per issue): %q", len(rows), body)
+	}
+	if strings.Contains(body, "foo | bar flag") {
+		t.Error("Render() did not escape the literal \"|\" in the summary")
+	}
+	if !strings.Contains(body, `foo \| bar flag`) {
+		t.Error("Render() expected the escaped pipe in the rendered summary")
+	}
+	if !strings.Contains(body, "with a second line") || strings.Contains(body, "flag\nwith") {
+		t.Error("Render() did not collapse the embedded newline onto a single line")
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jiraconfig holds the `jira` stanza of the plugin config. It is a
+// leaf package (it only imports prow/jira and prow/jira/auth) so that both
+// prow/plugins/jira and the core prow/plugins.Configuration it plugs into
+// (as a `Jira` field) can import it without prow/plugins/jira importing
+// prow/plugins importing prow/plugins/jira in a cycle.
+package jiraconfig
+
+import (
+	"fmt"
+
+	"k8s.io/test-infra/prow/jira/auth"
+
+	jiraclient "k8s.io/test-infra/prow/jira"
+)
+
+// Config lets a single Prow deployment talk to more than one Jira instance
+// (e.g. separate Red Hat and Apache tenants) and pick the right one per
+// org/repo, instead of requiring a dedicated hook deployment per Jira
+// instance.
+//
+// When Instances is empty, the plugin falls back to the single
+// plugins.Agent.JiraClient it has always used.
+type Config struct {
+	// Instances maps an arbitrary instance name to its connection details.
+	// The name is referenced by OrgDefaults and RepoOverrides below.
+	Instances map[string]InstanceConfig `json:"instances,omitempty"`
+	// OrgDefaults maps a GitHub org to the instance name it should use.
+	OrgDefaults map[string]string `json:"org_defaults,omitempty"`
+	// RepoOverrides maps a GitHub "org/repo" to the instance name it should
+	// use, taking precedence over OrgDefaults.
+	RepoOverrides map[string]string `json:"repo_overrides,omitempty"`
+
+	// Creators lists who may run `/jira create`: either a raw GitHub login
+	// or an org name (membership in the org grants access). Leave empty to
+	// disable issue creation from comments entirely.
+	Creators []string `json:"creators,omitempty"`
+
+	// Repos maps a GitHub "org/repo" to prefiltering options that cut down
+	// on false-positive issue-key matches (e.g. "PR-123" or "KEP-42") and
+	// on the number of Jira API calls per comment.
+	Repos map[string]RepoConfig `json:"repos,omitempty"`
+}
+
+// RepoConfig configures per-repo behavior for narrowing down which
+// `FOO-123`-shaped strings found in a comment are treated as real Jira
+// references.
+type RepoConfig struct {
+	// Projects restricts matching to these Jira project keys. If empty, any
+	// project known to the resolved Jira instance is eligible.
+	Projects []string `json:"projects,omitempty"`
+	// ExtraJQL is ANDed onto the JQL prefilter query used to resolve
+	// candidates, e.g. to restrict to a component or exclude a status.
+	ExtraJQL string `json:"extra_jql,omitempty"`
+
+	// Sync configures the one-way GitHub->Jira state sync triggered
+	// by PR and review lifecycle events. Leave the zero value to disable
+	// it for this repo.
+	Sync SyncConfig `json:"sync,omitempty"`
+}
+
+// SyncConfig declares how PR/review lifecycle events should transition
+// referenced Jira issues.
+type SyncConfig struct {
+	// Transitions maps a Jira project key to the transition names to apply
+	// for each GitHub lifecycle event. Transition names are project- and
+	// workflow-specific, so there is no sensible global default.
+	Transitions map[string]TransitionMap `json:"transitions,omitempty"`
+	// SkipIf lists GitHub labels that suppress all transitions for a PR,
+	// e.g. "do-not-merge".
+	SkipIf []string `json:"skip_if,omitempty"`
+	// DryRun logs the transition that would be made instead of applying it
+	// or commenting on the issue. Referenced issues are still looked up.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// TransitionMap names the Jira transition to apply for each GitHub
+// lifecycle event this plugin understands. An empty field means "don't
+// transition on this event".
+type TransitionMap struct {
+	Opened   string `json:"opened,omitempty"`
+	Merged   string `json:"merged,omitempty"`
+	Closed   string `json:"closed,omitempty"`
+	Approved string `json:"approved,omitempty"`
+}
+
+// TransitionNameFor returns the configured transition name for project on
+// the given lifecycle event kind ("opened", "merged", "closed", "approved").
+func (s SyncConfig) TransitionNameFor(project, kind string) (string, bool) {
+	name := ""
+	switch kind {
+	case "opened":
+		name = s.Transitions[project].Opened
+	case "merged":
+		name = s.Transitions[project].Merged
+	case "closed":
+		name = s.Transitions[project].Closed
+	case "approved":
+		name = s.Transitions[project].Approved
+	}
+	return name, name != ""
+}
+
+// RepoConfig returns the RepoConfig for org/repo, or the zero value if none
+// is configured.
+func (c Config) RepoConfig(org, repo string) RepoConfig {
+	return c.Repos[fmt.Sprintf("%s/%s", org, repo)]
+}
+
+// InstanceConfig describes a single Jira tenant.
+type InstanceConfig struct {
+	// URL is the base URL of the Jira instance, e.g. https://issues.redhat.com.
+	URL string `json:"url"`
+	// CredentialRef names a credential in the auth.Store used to
+	// authenticate against this instance.
+	CredentialRef string `json:"credential_ref"`
+}
+
+// NewClientResolver builds a jiraclient.ClientResolver out of this config
+// and a credential store, for use as plugins.Agent.JiraClientResolver. It
+// returns false if no instances are configured.
+func (c Config) NewClientResolver(store auth.Store) (jiraclient.ClientResolver, bool) {
+	if len(c.Instances) == 0 {
+		return nil, false
+	}
+	instances := make(jiraclient.InstanceMap, len(c.Instances))
+	for name, instance := range c.Instances {
+		instances[name] = jiraclient.Instance{URL: instance.URL, CredentialRef: instance.CredentialRef}
+	}
+	return jiraclient.NewClientFactory(instances, c.OrgDefaults, c.RepoOverrides, store), true
+}
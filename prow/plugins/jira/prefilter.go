@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+
+	jiraclient "k8s.io/test-infra/prow/jira"
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
+)
+
+// projectCacheTTL bounds how stale the live project-key set used to
+// prefilter candidates can be.
+const projectCacheTTL = 10 * time.Minute
+
+// projectCaches holds one jiraclient.ProjectCache per Jira instance,
+// indexed by its base URL, so a refresh is shared across all repos backed
+// by the same instance.
+var projectCaches sync.Map // map[string]*jiraclient.ProjectCache
+
+func projectCacheFor(jc jiraclient.Client) *jiraclient.ProjectCache {
+	if cache, ok := projectCaches.Load(jc.JiraURL()); ok {
+		return cache.(*jiraclient.ProjectCache)
+	}
+	cache := jiraclient.NewProjectCache(jc, projectCacheTTL)
+	actual, _ := projectCaches.LoadOrStore(jc.JiraURL(), cache)
+	return actual.(*jiraclient.ProjectCache)
+}
+
+// filterCandidates drops candidates whose project portion (the part before
+// the last "-") isn't an allowed project, without making any Jira API call.
+// repoConfig.Projects, when set, is authoritative; knownProjects (the live
+// project-key set, if available) further narrows an unrestricted repo.
+func filterCandidates(candidates []string, repoConfig jiraconfig.RepoConfig, knownProjects map[string]bool) []string {
+	allow := make(map[string]bool, len(repoConfig.Projects))
+	for _, project := range repoConfig.Projects {
+		allow[strings.ToUpper(project)] = true
+	}
+
+	var filtered []string
+	for _, candidate := range candidates {
+		project := projectOf(candidate)
+		if len(allow) > 0 && !allow[project] {
+			continue
+		}
+		if len(allow) == 0 && knownProjects != nil && !knownProjects[project] {
+			continue
+		}
+		filtered = append(filtered, candidate)
+	}
+	return filtered
+}
+
+func projectOf(issueKey string) string {
+	idx := strings.LastIndex(issueKey, "-")
+	if idx == -1 {
+		return strings.ToUpper(issueKey)
+	}
+	return strings.ToUpper(issueKey[:idx])
+}
+
+// resolveIssues looks up which of candidates are real Jira issues as a
+// single batched JQL query instead of one GetIssue call per candidate. The
+// map is keyed by the verbatim candidate string (not the issue's canonical-
+// case key), so callers can match it back against the original comment text.
+func resolveIssues(jc jiraclient.Client, candidates []string, repoConfig jiraconfig.RepoConfig) (map[string]*jira.Issue, error) {
+	found := map[string]*jira.Issue{}
+	if len(candidates) == 0 {
+		return found, nil
+	}
+
+	issues, err := jc.SearchIssues(buildJQL(candidates, repoConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jira for candidate issues: %w", err)
+	}
+	byCanonicalKey := make(map[string]*jira.Issue, len(issues))
+	for i := range issues {
+		byCanonicalKey[strings.ToUpper(issues[i].Key)] = &issues[i]
+	}
+	for _, candidate := range candidates {
+		if issue, ok := byCanonicalKey[strings.ToUpper(candidate)]; ok {
+			found[candidate] = issue
+		}
+	}
+	return found, nil
+}
+
+func buildJQL(candidates []string, repoConfig jiraconfig.RepoConfig) string {
+	jql := fmt.Sprintf("issuekey in (%s)", strings.Join(candidates, ","))
+	if len(repoConfig.Projects) > 0 {
+		jql = fmt.Sprintf("%s AND project in (%s)", jql, strings.Join(repoConfig.Projects, ","))
+	}
+	if repoConfig.ExtraJQL != "" {
+		jql = fmt.Sprintf("%s AND (%s)", jql, repoConfig.ExtraJQL)
+	}
+	return jql
+}
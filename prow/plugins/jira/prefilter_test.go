@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
+)
+
+func TestProjectOf(t *testing.T) {
+	testCases := []struct {
+		key  string
+		want string
+	}{
+		{key: "ABC-123", want: "ABC"},
+		{key: "abc-123", want: "ABC"},
+		{key: "no-dash-at-all", want: "NO-DASH-AT"},
+		{key: "NODASH", want: "NODASH"},
+	}
+	for _, tc := range testCases {
+		if got := projectOf(tc.key); got != tc.want {
+			t.Errorf("projectOf(%q) = %q, want %q", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestFilterCandidates(t *testing.T) {
+	testCases := []struct {
+		name          string
+		candidates    []string
+		repoConfig    jiraconfig.RepoConfig
+		knownProjects map[string]bool
+		want          []string
+	}{
+		{
+			name:       "no restrictions keeps everything",
+			candidates: []string{"ABC-1", "DEF-2"},
+			want:       []string{"ABC-1", "DEF-2"},
+		},
+		{
+			name:       "repoConfig.Projects is authoritative",
+			candidates: []string{"ABC-1", "DEF-2"},
+			repoConfig: jiraconfig.RepoConfig{Projects: []string{"abc"}},
+			want:       []string{"ABC-1"},
+		},
+		{
+			name:          "falls back to knownProjects when unrestricted",
+			candidates:    []string{"ABC-1", "DEF-2"},
+			knownProjects: map[string]bool{"ABC": true},
+			want:          []string{"ABC-1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := filterCandidates(tc.candidates, tc.repoConfig, tc.knownProjects)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("filterCandidates() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildJQL(t *testing.T) {
+	testCases := []struct {
+		name       string
+		candidates []string
+		repoConfig jiraconfig.RepoConfig
+		want       string
+	}{
+		{
+			name:       "no repo config",
+			candidates: []string{"ABC-1", "DEF-2"},
+			want:       "issuekey in (ABC-1,DEF-2)",
+		},
+		{
+			name:       "with projects and extra JQL",
+			candidates: []string{"ABC-1"},
+			repoConfig: jiraconfig.RepoConfig{Projects: []string{"ABC"}, ExtraJQL: "status != Closed"},
+			want:       "issuekey in (ABC-1) AND project in (ABC) AND (status != Closed)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := buildJQL(tc.candidates, tc.repoConfig); got != tc.want {
+				t.Errorf("buildJQL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeSearchClient is a jiraclient.Client stub whose SearchIssues returns a
+// fixed set of issues, for exercising resolveIssues without a live Jira.
+type fakeSearchClient struct {
+	fakeJiraClient
+	issues []jira.Issue
+}
+
+func (f *fakeSearchClient) SearchIssues(jql string) ([]jira.Issue, error) {
+	return f.issues, nil
+}
+
+func TestResolveIssuesPreservesCandidateCase(t *testing.T) {
+	jc := &fakeSearchClient{issues: []jira.Issue{{Key: "ABC-123"}}}
+
+	found, err := resolveIssues(jc, []string{"abc-123"}, jiraconfig.RepoConfig{})
+	if err != nil {
+		t.Fatalf("resolveIssues() returned unexpected error: %v", err)
+	}
+
+	if _, ok := found["abc-123"]; !ok {
+		t.Fatalf("resolveIssues() keys = %v, want the verbatim candidate %q preserved", keysOf(found), "abc-123")
+	}
+	if _, ok := found["ABC-123"]; ok {
+		t.Error("resolveIssues() should key by the verbatim candidate, not the canonical-case issue key")
+	}
+}
+
+func keysOf(m map[string]*jira.Issue) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	jiraclient "k8s.io/test-infra/prow/jira"
+	"k8s.io/test-infra/prow/plugins"
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
+)
+
+func init() {
+	plugins.RegisterPullRequestHandler(PluginName, handlePullRequestEvent, helpProvider)
+	plugins.RegisterReviewEventHandler(PluginName, handlePullRequestReviewEvent, helpProvider)
+}
+
+func handlePullRequestEvent(pc plugins.Agent, pe github.PullRequestEvent) error {
+	var kind string
+	switch {
+	case pe.Action == github.PullRequestActionOpened:
+		kind = "opened"
+	case pe.Action == github.PullRequestActionClosed && pe.PullRequest.Merged:
+		kind = "merged"
+	case pe.Action == github.PullRequestActionClosed:
+		kind = "closed"
+	default:
+		return nil
+	}
+	return syncJiraState(pc, pe.Repo, pe.PullRequest, kind)
+}
+
+func handlePullRequestReviewEvent(pc plugins.Agent, re github.ReviewEvent) error {
+	if re.Review.State != github.ReviewStateApproved {
+		return nil
+	}
+	return syncJiraState(pc, re.Repo, re.PullRequest, "approved")
+}
+
+// syncJiraState transitions every Jira issue referenced by pr's title/body
+// according to repoConfig.Sync, recording each transition as a Jira comment
+// carrying the GitHub URL.
+func syncJiraState(pc plugins.Agent, repo github.Repo, pr github.PullRequest, kind string) error {
+	repoConfig := pc.PluginConfig.Jira.RepoConfig(repo.Owner.Login, repo.Name)
+	if len(repoConfig.Sync.Transitions) == 0 {
+		return nil
+	}
+	if hasAnyLabel(pr.Labels, repoConfig.Sync.SkipIf) {
+		return nil
+	}
+
+	jc, err := resolveClient(pc, repo.Owner.Login, repo.Name)
+	if err != nil {
+		pc.Logger.WithError(err).WithField("repo", repo.FullName).Debug("No jira instance configured for repo, ignoring PR event")
+		return nil
+	}
+
+	issueCandidateNames := issueNameRegex.FindAllString(pr.Body, -1)
+	issueCandidateNames = append(issueCandidateNames, issueNameRegex.FindAllString(pr.Title, -1)...)
+	candidates := sets.NewString(issueCandidateNames...).List()
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	knownProjects, err := projectCacheFor(jc).Keys()
+	if err != nil {
+		pc.Logger.WithError(err).Warn("Failed to refresh jira project keys, falling back to configured allow-list only")
+		knownProjects = nil
+	}
+	candidates = filterCandidates(candidates, repoConfig, knownProjects)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	found, err := resolveIssues(jc, candidates, repoConfig)
+	if err != nil {
+		return fmt.Errorf("failed to resolve candidate jira issues: %w", err)
+	}
+
+	for key := range found {
+		if err := transitionIssue(jc, pc.Logger, key, kind, repoConfig.Sync, pr.HTMLURL); err != nil {
+			pc.Logger.WithField("Issue", key).WithError(err).Error("Failed to transition jira issue")
+		}
+	}
+	return nil
+}
+
+func hasAnyLabel(labels []github.Label, skipIf []string) bool {
+	if len(skipIf) == 0 {
+		return false
+	}
+	skip := sets.NewString(skipIf...)
+	for _, label := range labels {
+		if skip.Has(label.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionIssue moves issue to the transition configured for kind, if
+// any, and records the move as a Jira comment linking back to prURL.
+func transitionIssue(jc jiraclient.Client, log *logrus.Entry, issueKey, kind string, sync jiraconfig.SyncConfig, prURL string) error {
+	transitionName, ok := sync.TransitionNameFor(projectOf(issueKey), kind)
+	if !ok {
+		return nil
+	}
+
+	if sync.DryRun {
+		log.WithField("Issue", issueKey).Infof("[dry-run] would transition to %q (%s)", transitionName, prURL)
+		return nil
+	}
+
+	transitions, err := jc.GetTransitions(issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to get transitions: %w", err)
+	}
+	var transitionID string
+	for _, t := range transitions {
+		if t.Name == transitionName {
+			transitionID = t.ID
+			break
+		}
+	}
+	if transitionID == "" {
+		return fmt.Errorf("transition %q is not available on issue %s", transitionName, issueKey)
+	}
+
+	if err := jc.DoTransition(issueKey, transitionID); err != nil {
+		return fmt.Errorf("failed to apply transition %q: %w", transitionName, err)
+	}
+	if err := jc.AddComment(issueKey, fmt.Sprintf("Transitioned to %q by %s", transitionName, prURL)); err != nil {
+		log.WithField("Issue", issueKey).WithError(err).Error("Failed to record transition comment")
+	}
+	return nil
+}
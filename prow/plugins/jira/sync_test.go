@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/plugins/jira/jiraconfig"
+)
+
+// fakeJiraClient is a minimal in-memory jiraclient.Client for exercising
+// sync.go without a live Jira instance.
+type fakeJiraClient struct {
+	url          string
+	transitions  map[string][]jira.Transition
+	transitioned string
+	comments     []string
+}
+
+func (f *fakeJiraClient) JiraURL() string { return f.url }
+func (f *fakeJiraClient) GetIssue(id string) (*jira.Issue, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (f *fakeJiraClient) GetIssueWithFields(id string, fields ...string) (*jira.Issue, error) {
+	return f.GetIssue(id)
+}
+func (f *fakeJiraClient) GetRemoteLinks(id string) ([]jira.RemoteLink, error) {
+	return nil, nil
+}
+func (f *fakeJiraClient) AddRemoteLink(id string, link *jira.RemoteLink) error { return nil }
+func (f *fakeJiraClient) GetProjects() ([]jira.Project, error)                 { return nil, nil }
+func (f *fakeJiraClient) SearchIssues(jql string) ([]jira.Issue, error)        { return nil, nil }
+func (f *fakeJiraClient) CreateIssue(project, issueType, summary, description string) (*jira.Issue, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (f *fakeJiraClient) DeleteRemoteLink(id string, linkID int) error { return nil }
+
+func (f *fakeJiraClient) GetTransitions(id string) ([]jira.Transition, error) {
+	return f.transitions[id], nil
+}
+
+func (f *fakeJiraClient) DoTransition(id, transitionID string) error {
+	f.transitioned = transitionID
+	return nil
+}
+
+func (f *fakeJiraClient) AddComment(id, comment string) error {
+	f.comments = append(f.comments, comment)
+	return nil
+}
+
+func TestHasAnyLabel(t *testing.T) {
+	labels := []github.Label{{Name: "do-not-merge"}, {Name: "lgtm"}}
+
+	if hasAnyLabel(labels, nil) {
+		t.Error("hasAnyLabel() with no skipIf expected false, got true")
+	}
+	if !hasAnyLabel(labels, []string{"do-not-merge"}) {
+		t.Error("hasAnyLabel() expected true for a matching label, got false")
+	}
+	if hasAnyLabel(labels, []string{"hold"}) {
+		t.Error("hasAnyLabel() expected false for a non-matching label, got true")
+	}
+}
+
+func TestTransitionIssue(t *testing.T) {
+	sync := jiraconfig.SyncConfig{
+		Transitions: map[string]jiraconfig.TransitionMap{
+			"ABC": {Merged: "Done"},
+		},
+	}
+	jc := &fakeJiraClient{
+		transitions: map[string][]jira.Transition{
+			"ABC-123": {{ID: "31", Name: "Done"}, {ID: "21", Name: "In Progress"}},
+		},
+	}
+	log := logrus.NewEntry(logrus.StandardLogger())
+
+	if err := transitionIssue(jc, log, "ABC-123", "merged", sync, "https://github.com/org/repo/pull/1"); err != nil {
+		t.Fatalf("transitionIssue() returned unexpected error: %v", err)
+	}
+	if jc.transitioned != "31" {
+		t.Errorf("transitioned = %q, want %q", jc.transitioned, "31")
+	}
+	if len(jc.comments) != 1 {
+		t.Fatalf("got %d comments, want 1", len(jc.comments))
+	}
+
+	// No transition configured for this event kind: no-op, not an error.
+	jc = &fakeJiraClient{transitions: map[string][]jira.Transition{"ABC-123": {{ID: "31", Name: "Done"}}}}
+	if err := transitionIssue(jc, log, "ABC-123", "opened", sync, "https://github.com/org/repo/pull/1"); err != nil {
+		t.Fatalf("transitionIssue() for unconfigured kind returned unexpected error: %v", err)
+	}
+	if jc.transitioned != "" {
+		t.Error("transitionIssue() for unconfigured kind should not have transitioned the issue")
+	}
+
+	// Configured transition name isn't available on the issue: error.
+	jc = &fakeJiraClient{transitions: map[string][]jira.Transition{"ABC-123": {{ID: "21", Name: "In Progress"}}}}
+	if err := transitionIssue(jc, log, "ABC-123", "merged", sync, "https://github.com/org/repo/pull/1"); err == nil {
+		t.Error("transitionIssue() expected an error when the configured transition isn't available, got nil")
+	}
+
+	// DryRun never calls the client.
+	jc = &fakeJiraClient{transitions: map[string][]jira.Transition{"ABC-123": {{ID: "31", Name: "Done"}}}}
+	dryRunSync := jiraconfig.SyncConfig{Transitions: sync.Transitions, DryRun: true}
+	if err := transitionIssue(jc, log, "ABC-123", "merged", dryRunSync, "https://github.com/org/repo/pull/1"); err != nil {
+		t.Fatalf("transitionIssue() in dry-run returned unexpected error: %v", err)
+	}
+	if jc.transitioned != "" {
+		t.Error("transitionIssue() in dry-run should not have transitioned the issue")
+	}
+}